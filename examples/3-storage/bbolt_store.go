@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"go.etcd.io/bbolt"
+)
+
+// todoItemsBucket is the single bbolt bucket BoltStore keeps its items in.
+var todoItemsBucket = []byte("todo-items")
+
+// BoltStore is a Store backed by a bbolt database file, so todo items
+// survive a restart of the server (MemoryStore's do not). bbolt is an
+// embedded, single-file key/value store - no separate database process to
+// run, which keeps this chapter's "just go run it" feel intact.
+//
+// Keys are the item's id, encoded as an 8-byte big-endian uint64 so bbolt's
+// byte-wise key ordering matches numeric id ordering. Values are the
+// TodoItem, JSON-encoded.
+type BoltStore struct {
+	db *bbolt.DB
+}
+
+// NewBoltStore opens (creating if necessary) a bbolt database at path and
+// ensures the todo items bucket exists.
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bbolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("open bbolt database: %w", err)
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(todoItemsBucket)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create todo items bucket: %w", err)
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+// Close releases the underlying database file.
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+func itob(id int) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(id))
+	return buf
+}
+
+func (s *BoltStore) List() ([]TodoItem, error) {
+	var items []TodoItem
+
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(todoItemsBucket)
+		return bucket.ForEach(func(k, v []byte) error {
+			var item TodoItem
+			if err := json.Unmarshal(v, &item); err != nil {
+				return err
+			}
+			items = append(items, item)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if items == nil {
+		items = make([]TodoItem, 0)
+	}
+	return items, nil
+}
+
+func (s *BoltStore) Get(id int) (TodoItem, error) {
+	var item TodoItem
+
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		v := tx.Bucket(todoItemsBucket).Get(itob(id))
+		if v == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(v, &item)
+	})
+
+	return item, err
+}
+
+func (s *BoltStore) Create(item TodoItem) (int, error) {
+	var id int
+
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(todoItemsBucket)
+
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		id = int(seq) - 1
+
+		v, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		return bucket.Put(itob(id), v)
+	})
+
+	return id, err
+}
+
+func (s *BoltStore) Replace(id int, item TodoItem) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		v, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(todoItemsBucket).Put(itob(id), v)
+	})
+}
+
+func (s *BoltStore) Update(id int, content *string, done *bool) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(todoItemsBucket)
+
+		v := bucket.Get(itob(id))
+		if v == nil {
+			return ErrNotFound
+		}
+
+		var item TodoItem
+		if err := json.Unmarshal(v, &item); err != nil {
+			return err
+		}
+
+		if content != nil {
+			item.Content = *content
+		}
+		if done != nil {
+			item.Done = *done
+		}
+
+		updated, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		return bucket.Put(itob(id), updated)
+	})
+}
+
+func (s *BoltStore) Delete(id int) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(todoItemsBucket)
+		if bucket.Get(itob(id)) == nil {
+			return ErrNotFound
+		}
+		return bucket.Delete(itob(id))
+	})
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+)
+
+var (
+	port   = flag.Int("p", 8080, "port")
+	dbPath = flag.String("db", "", "path to a bbolt database file; if empty, an in-memory store is used")
+)
+
+func main() {
+	defer recoverer()
+	flag.Parse()
+
+	// this is the only place that knows (or cares) which Store
+	// implementation is in play. everything downstream - the handlers,
+	// the router - talks to the `Store` interface from store.go.
+	store, closeStore, err := newStore(*dbPath)
+	if err != nil {
+		panic(err)
+	}
+	defer closeStore()
+
+	api := &api{store: store}
+
+	router := http.NewServeMux()
+	router.HandleFunc("GET /todo-item", api.getTodoItems)
+	router.HandleFunc("GET /todo-item/{id}", api.getTodoItem)
+	router.HandleFunc("HEAD /todo-item/{id}", api.getTodoItemExists)
+	router.HandleFunc("POST /todo-item", api.createTodoItem)
+	router.HandleFunc("PUT /todo-item/{id}", api.replaceTodoItem)
+	router.HandleFunc("PATCH /todo-item/{id}", api.updateTodoItem)
+	router.HandleFunc("DELETE /todo-item/{id}", api.deleteTodoItem)
+
+	server := http.Server{
+		Addr:    fmt.Sprintf(":%d", *port),
+		Handler: router,
+	}
+
+	fmt.Printf("listening on port %s\n", server.Addr)
+
+	go func() {
+		err := server.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
+			panic(err)
+		}
+	}()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	<-ctx.Done()
+
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	if err := server.Shutdown(ctx); err != nil {
+		panic(err)
+	}
+
+	fmt.Println("goodbye :)")
+}
+
+// newStore picks a Store implementation based on -db: an in-memory store
+// with no argument (handy for quick demos and tests), or a bbolt-backed
+// store that persists todo items across restarts when a path is given. It
+// returns a no-op close func for MemoryStore so callers don't need a type
+// switch to clean up.
+func newStore(path string) (store Store, closeFunc func() error, err error) {
+	if path == "" {
+		return NewMemoryStore(), func() error { return nil }, nil
+	}
+
+	bolt, err := NewBoltStore(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return bolt, bolt.Close, nil
+}
+
+// api holds the dependencies the todo-item handlers need. dependency
+// injecting the Store here (rather than reaching for a package-level
+// variable, as [2-routing] did) is what lets the exact same handlers run
+// against MemoryStore in tests and BoltStore in production.
+type api struct {
+	store Store
+}
+
+func (a *api) getTodoItems(w http.ResponseWriter, r *http.Request) {
+	items, err := a.store.List()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(items)
+}
+
+func (a *api) getTodoItem(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("`id` must be of type int"))
+		return
+	}
+
+	item, err := a.store.Get(id)
+	if errors.Is(err, ErrNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("`id` not found"))
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(item)
+}
+
+func (a *api) getTodoItemExists(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("`id` must be of type int"))
+		return
+	}
+
+	if _, err := a.store.Get(id); errors.Is(err, ErrNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (a *api) createTodoItem(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("invalid form"))
+		return
+	}
+
+	content := r.FormValue("content")
+	done, err := strconv.ParseBool(r.FormValue("done"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("`done` must be of type bool"))
+		return
+	}
+
+	id, err := a.store.Create(TodoItem{Content: content, Done: done})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	location := fmt.Sprintf("%s/todo-item/%d", r.URL.Host, id)
+	w.Header().Add("Location", location)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (a *api) replaceTodoItem(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("`id` must be of type int"))
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("invalid form"))
+		return
+	}
+
+	content := r.FormValue("content")
+	done, err := strconv.ParseBool(r.FormValue("done"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("`done` must be of type bool"))
+		return
+	}
+
+	err = a.store.Replace(id, TodoItem{Content: content, Done: done})
+	if errors.Is(err, ErrNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("`id` not found"))
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	location := fmt.Sprintf("%s/todo-item/%d", r.URL.Host, id)
+	w.Header().Add("Location", location)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (a *api) updateTodoItem(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("`id` must be of type int"))
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("invalid form"))
+		return
+	}
+
+	var content *string
+	if v := r.FormValue("content"); v != "" {
+		content = &v
+	}
+
+	var done *bool
+	if v := r.FormValue("done"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("`done` must be of type bool"))
+			return
+		}
+		done = &parsed
+	}
+
+	err = a.store.Update(id, content, done)
+	if errors.Is(err, ErrNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("`id` not found"))
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (a *api) deleteTodoItem(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("`id` must be of type int"))
+		return
+	}
+
+	err = a.store.Delete(id)
+	if errors.Is(err, ErrNotFound) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("`id` not found"))
+		return
+	} else if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func recoverer() {
+	if r := recover(); r != nil {
+		fmt.Fprintf(os.Stderr, "unexpected panic occurred: %v", r)
+		os.Exit(1)
+	}
+}
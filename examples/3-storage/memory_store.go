@@ -0,0 +1,108 @@
+package main
+
+import (
+	"sort"
+	"sync"
+)
+
+// MemoryStore is a Store backed by a map guarded by a sync.RWMutex. Reads
+// (List, Get) take the read lock and can run concurrently with each other;
+// writes (Create, Replace, Update, Delete) take the write lock and run
+// exclusively. Unlike the package-level `todoItems` slice in [2-routing],
+// every access goes through the mutex, so `go test -race` has nothing to
+// complain about.
+//
+// Ids come from a monotonically increasing counter and are never reused
+// or renumbered, the same as BoltStore's bucket.NextSequence() - so
+// Delete(0) removes exactly item 0 and leaves every other id's mapping
+// untouched, instead of shifting every later item down by one the way a
+// plain slice would.
+type MemoryStore struct {
+	mu     sync.RWMutex
+	items  map[int]TodoItem
+	nextID int
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{items: make(map[int]TodoItem)}
+}
+
+func (s *MemoryStore) List() ([]TodoItem, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := make([]int, 0, len(s.items))
+	for id := range s.items {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	items := make([]TodoItem, len(ids))
+	for i, id := range ids {
+		items[i] = s.items[id]
+	}
+	return items, nil
+}
+
+func (s *MemoryStore) Get(id int) (TodoItem, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	item, ok := s.items[id]
+	if !ok {
+		return TodoItem{}, ErrNotFound
+	}
+	return item, nil
+}
+
+func (s *MemoryStore) Create(item TodoItem) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.nextID
+	s.nextID++
+	s.items[id] = item
+	return id, nil
+}
+
+// Replace overwrites the item at id, or creates it if id doesn't exist
+// yet - matching BoltStore.Replace, which Puts unconditionally. This is
+// what store.go's doc comment promises, and what makes `PUT
+// /todo-item/{id}` on an unknown id behave the same way (a 200, not a
+// 404) regardless of which backend is behind the handler.
+func (s *MemoryStore) Replace(id int, item TodoItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.items[id] = item
+	return nil
+}
+
+func (s *MemoryStore) Update(id int, content *string, done *bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.items[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if content != nil {
+		item.Content = *content
+	}
+	if done != nil {
+		item.Done = *done
+	}
+	s.items[id] = item
+	return nil
+}
+
+func (s *MemoryStore) Delete(id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.items[id]; !ok {
+		return ErrNotFound
+	}
+	delete(s.items, id)
+	return nil
+}
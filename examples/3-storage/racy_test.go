@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"sync"
+	"testing"
+)
+
+// racySlice reproduces the storage approach from [2-routing]: a bare slice
+// mutated directly by concurrent callers, with no locking whatsoever.
+var racySlice = make([]TodoItem, 0)
+
+// TestTodoItemsSliceIsRacy is the reproducer mentioned in store.go. It's
+// deliberately racy, so it's skipped by default - `go test -race ./...`
+// would otherwise fail on this package every single time, for a "bug" we
+// put there on purpose. Run it explicitly with the race detector enabled:
+//
+//	RACE_DEMO=1 go test -race ./examples/3-storage/ -run TestTodoItemsSliceIsRacy
+//
+// One goroutine appends to racySlice (growing it, and occasionally
+// reallocating its backing array) while another reads its length and
+// indexes into it - exactly what `createTodoItem` and `getTodoItems` did
+// concurrently in [2-routing]. The race detector reliably flags this as a
+// DATA RACE; MemoryStore below fixes it with a sync.RWMutex instead.
+func TestTodoItemsSliceIsRacy(t *testing.T) {
+	if os.Getenv("RACE_DEMO") == "" {
+		t.Skip("deliberately racy reproducer; set RACE_DEMO=1 to run it")
+	}
+
+	var wg sync.WaitGroup
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			racySlice = append(racySlice, TodoItem{Content: "race me"})
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			if n := len(racySlice); n > 0 {
+				_ = racySlice[n-1]
+			}
+		}
+	}()
+
+	wg.Wait()
+}
+
+// TestMemoryStoreIsRaceFree runs the same concurrent create/list workload
+// against MemoryStore, which guards its map with a sync.RWMutex. This
+// should pass cleanly under -race.
+func TestMemoryStoreIsRaceFree(t *testing.T) {
+	store := NewMemoryStore()
+	var wg sync.WaitGroup
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			if _, err := store.Create(TodoItem{Content: "race me"}); err != nil {
+				t.Error(err)
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			if _, err := store.List(); err != nil {
+				t.Error(err)
+			}
+		}
+	}()
+
+	wg.Wait()
+}
@@ -0,0 +1,43 @@
+package main
+
+import "errors"
+
+// ErrNotFound is returned by a Store when the requested item does not exist.
+var ErrNotFound = errors.New("todo item not found")
+
+type TodoItem struct {
+	Content string
+	Done    bool
+}
+
+// Store abstracts the persistence of TodoItems away from the HTTP handlers.
+//
+// [2-routing] mutated a package-level `todoItems` slice directly from every
+// handler. That works fine for a single-threaded demo, but `net/http` serves
+// each request on its own goroutine, so two requests landing at the same
+// time (say, a POST racing a GET) read and write the slice header and its
+// backing array with no synchronization at all. Run
+// `RACE_DEMO=1 go test -race -run TestTodoItemsSliceIsRacy` in this package
+// and watch `TestTodoItemsSliceIsRacy` prove it (it's skipped without
+// RACE_DEMO).
+//
+// By hiding storage behind an interface, the handlers in main.go don't know
+// or care whether items live in a mutex-guarded map in memory or in a
+// bbolt database on disk - they just call Store methods. That's what lets
+// us swap MemoryStore for BoltStore (see memory_store.go and
+// bbolt_store.go) without touching a single handler.
+type Store interface {
+	// List returns every todo item, ordered by id.
+	List() ([]TodoItem, error)
+	// Get returns the todo item with the given id, or ErrNotFound.
+	Get(id int) (TodoItem, error)
+	// Create appends a new todo item and returns its assigned id.
+	Create(item TodoItem) (int, error)
+	// Replace overwrites (or creates) the todo item at id.
+	Replace(id int, item TodoItem) error
+	// Update applies a partial update to the todo item at id, or
+	// ErrNotFound if it doesn't exist.
+	Update(id int, content *string, done *bool) error
+	// Delete removes the todo item at id, or ErrNotFound if it doesn't exist.
+	Delete(id int) error
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestStoreIDsAreStableAcrossDelete is the contract memory_store.go's doc
+// comment spells out:
+// deleting an item must not change any other item's id, for any Store
+// implementation. A slice-backed store that shifts elements down on
+// delete would fail this for both MemoryStore and BoltStore.
+func TestStoreIDsAreStableAcrossDelete(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			firstID, err := store.Create(TodoItem{Content: "first"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			secondID, err := store.Create(TodoItem{Content: "second"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := store.Delete(firstID); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := store.Get(firstID); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("Get(%d) after delete: got err %v, want ErrNotFound", firstID, err)
+			}
+
+			item, err := store.Get(secondID)
+			if err != nil {
+				t.Fatalf("Get(%d) after deleting a different id: %v", secondID, err)
+			}
+			if item.Content != "second" {
+				t.Fatalf("Get(%d) after deleting a different id: got %q, want %q", secondID, item.Content, "second")
+			}
+		})
+	}
+}
+
+// TestStoreReplaceCreatesMissingID is the other half of the contract in
+// store.go's doc comment: Replace on an id that doesn't exist yet
+// creates it rather than returning ErrNotFound, for any Store
+// implementation. A `PUT /todo-item/{id}` on an unknown id must behave
+// the same way (200, item created) regardless of which backend is
+// behind the handler.
+func TestStoreReplaceCreatesMissingID(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			const missingID = 42
+
+			if err := store.Replace(missingID, TodoItem{Content: "new"}); err != nil {
+				t.Fatalf("Replace(%d, ...) on a missing id: %v", missingID, err)
+			}
+
+			item, err := store.Get(missingID)
+			if err != nil {
+				t.Fatalf("Get(%d) after Replace created it: %v", missingID, err)
+			}
+			if item.Content != "new" {
+				t.Fatalf("Get(%d) after Replace created it: got %q, want %q", missingID, item.Content, "new")
+			}
+		})
+	}
+}
+
+func testStores(t *testing.T) map[string]Store {
+	boltPath := filepath.Join(t.TempDir(), "todo.db")
+	bolt, err := NewBoltStore(boltPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { bolt.Close() })
+	t.Cleanup(func() { os.Remove(boltPath) })
+
+	return map[string]Store{
+		"MemoryStore": NewMemoryStore(),
+		"BoltStore":   bolt,
+	}
+}
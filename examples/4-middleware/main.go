@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type TodoItem struct {
+	Content string
+	Done    bool
+}
+
+var port = flag.Int("p", 8080, "port")
+
+// todoItems is guarded by mu, same fix as [3-storage]. this chapter is
+// about the handler boilerplate, not storage, so we keep the simple
+// mutex-guarded slice rather than pulling in the whole Store interface.
+var (
+	mu        sync.RWMutex
+	todoItems = make([]TodoItem, 0)
+)
+
+func main() {
+	flag.Parse()
+
+	router := http.NewServeMux()
+	router.HandleFunc("GET /todo-item", getTodoItems)
+	router.HandleFunc("GET /todo-item/{id}", getTodoItem)
+	router.HandleFunc("HEAD /todo-item/{id}", getTodoItemExists)
+	router.HandleFunc("POST /todo-item", createTodoItem)
+	router.HandleFunc("PUT /todo-item/{id}", replaceTodoItem)
+	router.HandleFunc("PATCH /todo-item/{id}", updateTodoItem)
+	router.HandleFunc("DELETE /todo-item/{id}", deleteTodoItem)
+
+	// compare this to the inlined `defer recoverer()` in [2-routing]:
+	// panic recovery, access logging, request ids and timeouts are now
+	// composable middlewares, applied once here for every route. the
+	// bad-request/not-found responses are still written by each handler
+	// below. RequestID comes first so the id is already in the request's
+	// context when Logger logs it.
+	handler := Chain(router,
+		RequestID,
+		Logger,
+		Recoverer,
+		Timeout(5*time.Second),
+	)
+
+	server := http.Server{
+		Addr:    fmt.Sprintf(":%d", *port),
+		Handler: handler,
+	}
+
+	fmt.Printf("listening on port %s\n", server.Addr)
+
+	go func() {
+		err := server.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
+			panic(err)
+		}
+	}()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	<-ctx.Done()
+
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	if err := server.Shutdown(ctx); err != nil {
+		panic(err)
+	}
+
+	fmt.Println("goodbye :)")
+}
+
+func getTodoItems(w http.ResponseWriter, r *http.Request) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	_ = json.NewEncoder(w).Encode(todoItems)
+}
+
+func getTodoItem(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("`id` must be of type int"))
+		return
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if id < 0 || id > len(todoItems)-1 {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("`id` not found"))
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(todoItems[id])
+}
+
+func getTodoItemExists(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("`id` must be of type int"))
+		return
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if id < 0 || id > len(todoItems)-1 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func createTodoItem(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("invalid form"))
+		return
+	}
+
+	content := r.FormValue("content")
+	done, err := strconv.ParseBool(r.FormValue("done"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("`done` must be of type bool"))
+		return
+	}
+
+	mu.Lock()
+	todoItems = append(todoItems, TodoItem{Content: content, Done: done})
+	id := len(todoItems) - 1
+	mu.Unlock()
+
+	location := fmt.Sprintf("%s/todo-item/%d", r.URL.Host, id)
+	w.Header().Add("Location", location)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func replaceTodoItem(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("`id` must be of type int"))
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("invalid form"))
+		return
+	}
+
+	content := r.FormValue("content")
+	done, err := strconv.ParseBool(r.FormValue("done"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("`done` must be of type bool"))
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if id < 0 || id > len(todoItems)-1 {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("`id` not found"))
+		return
+	}
+
+	todoItems[id] = TodoItem{Content: content, Done: done}
+
+	location := fmt.Sprintf("%s/todo-item/%d", r.URL.Host, id)
+	w.Header().Add("Location", location)
+	w.WriteHeader(http.StatusOK)
+}
+
+func updateTodoItem(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("`id` must be of type int"))
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("invalid form"))
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if id < 0 || id > len(todoItems)-1 {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("`id` not found"))
+		return
+	}
+
+	if content := r.FormValue("content"); content != "" {
+		todoItems[id].Content = content
+	}
+
+	if doneStr := r.FormValue("done"); doneStr != "" {
+		done, err := strconv.ParseBool(doneStr)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("`done` must be of type bool"))
+			return
+		}
+		todoItems[id].Done = done
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func deleteTodoItem(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("`id` must be of type int"))
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if id < 0 || id > len(todoItems)-1 {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("`id` not found"))
+		return
+	}
+
+	todoItems = append(todoItems[:id], todoItems[id+1:]...)
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"time"
+)
+
+// Middleware wraps an http.Handler to add behavior (logging, recovering
+// from panics, etc.) before and/or after the wrapped handler runs. It's
+// the standard `func(http.Handler) http.Handler` shape, which is what lets
+// middlewares from completely unrelated packages compose with Chain below.
+type Middleware func(http.Handler) http.Handler
+
+// Chain applies middlewares to handler in the order they're given, so the
+// first middleware passed is the outermost - the first to see the request
+// and the last to see the response.
+//
+//	Chain(handler, logger, recoverer, requestID)
+//	// is equivalent to:
+//	logger(recoverer(requestID(handler)))
+func Chain(handler http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// statusWriter records the status code written so Logger can log it;
+// http.ResponseWriter has no way to ask what was already written.
+type statusWriter struct {
+	http.ResponseWriter
+	status       int
+	bytesWritten int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytesWritten += n
+	return n, err
+}
+
+// Logger is a structured access-log middleware: method, path, status,
+// bytes written, and how long the handler took.
+func Logger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sw := &statusWriter{ResponseWriter: w}
+		start := time.Now()
+
+		next.ServeHTTP(sw, r)
+
+		fmt.Printf("%s %s %d %dB %s request_id=%s\n",
+			r.Method, r.URL.Path, sw.status, sw.bytesWritten,
+			time.Since(start), RequestIDFromContext(r.Context()))
+	})
+}
+
+// Recoverer replaces the old `defer recoverer()` pattern from
+// [1-shutdown] and [2-routing], which logged a panic and then called
+// os.Exit(1) - killing the whole process over one bad request. Instead,
+// it recovers locally to the request's goroutine, writes a 500, and logs
+// the stack trace so the server stays up for every other in-flight and
+// future request.
+func Recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				fmt.Printf("panic serving %s %s: %v\n%s\n", r.Method, r.URL.Path, rec, debug.Stack())
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requestIDKey is an unexported type so context keys from this package
+// can never collide with keys from another package using the same
+// underlying string - see the context.Context docs on WithValue.
+type requestIDKey struct{}
+
+var requestIDSeq = make(chan int, 1)
+
+func init() {
+	requestIDSeq <- 0
+}
+
+// nextRequestID hands out a small, process-local, monotonically
+// increasing id. It's not a UUID - the point of this chapter is the
+// propagation pattern, not the id format.
+func nextRequestID() string {
+	n := <-requestIDSeq
+	n++
+	requestIDSeq <- n
+	return fmt.Sprintf("%d", n)
+}
+
+// RequestID assigns every request an id (reusing one supplied by an
+// upstream proxy in the X-Request-Id header, if present), stores it in
+// the request's context, and echoes it back in the response header so
+// clients can correlate their request with server-side logs.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-Request-Id")
+		if id == "" {
+			id = nextRequestID()
+		}
+
+		w.Header().Set("X-Request-Id", id)
+		ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequestIDFromContext returns the request id stashed by RequestID, or
+// "-" if none is present (e.g. the request never passed through it).
+func RequestIDFromContext(ctx context.Context) string {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	if !ok {
+		return "-"
+	}
+	return id
+}
+
+// Timeout wraps the request's context in a context.WithTimeout, so
+// handlers that respect ctx.Done() (e.g. anything making a downstream
+// call) are cut off after d rather than running indefinitely.
+func Timeout(d time.Duration) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"crypto/tls"
+	"net/http"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// autocertTLSConfig builds a tls.Config backed by autocert.Manager, which
+// fetches and renews certificates from Let's Encrypt for the given
+// domains on demand - no cert.pem/key.pem to generate or manage
+// ourselves, unlike ensureSelfSignedCert. This only works for domains
+// that actually resolve to this machine on the public internet and
+// answer the ACME HTTP-01 challenge on port 80, which is why main.go
+// only wires this up when -autocert-domain is set; otherwise it falls
+// back to the self-signed certificate from selfsigned.go.
+//
+// The returned handler answers ACME HTTP-01 challenges and otherwise
+// redirects to HTTPS - main.go mounts it on the plain-http (-http-p)
+// server in place of redirectToHTTPS.
+func autocertTLSConfig(cacheDir string, domains ...string) (*tls.Config, http.Handler) {
+	manager := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(domains...),
+		Cache:      autocert.DirCache(cacheDir),
+	}
+
+	return manager.TLSConfig(), manager.HTTPHandler(nil)
+}
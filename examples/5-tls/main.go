@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+)
+
+var (
+	httpsPort = flag.Int("p", 8443, "https port")
+	httpPort  = flag.Int("http-p", 8080, "http port, used only to redirect to https")
+
+	certFile = flag.String("cert", "cert.pem", "path to TLS certificate (generated if missing)")
+	keyFile  = flag.String("key", "key.pem", "path to TLS private key (generated if missing)")
+
+	autocertDomain = flag.String("autocert-domain", "", "if set, fetch a real certificate from Let's Encrypt for this domain instead of self-signing")
+	autocertCache  = flag.String("autocert-cache", "autocert-cache", "directory autocert uses to cache certificates")
+)
+
+func main() {
+	defer recoverer()
+	flag.Parse()
+
+	var (
+		tlsConfig    *tls.Config
+		redirectHTTP http.Handler
+	)
+
+	if *autocertDomain != "" {
+		config, handler := autocertTLSConfig(*autocertCache, *autocertDomain)
+		tlsConfig, redirectHTTP = config, handler
+	} else {
+		cert, err := ensureSelfSignedCert(*certFile, *keyFile)
+		if err != nil {
+			panic(err)
+		}
+		// curated rather than the full default list: these are the
+		// suites the Go TLS package itself recommends for TLS 1.2,
+		// all of which support forward secrecy. TLS 1.3's suites
+		// aren't configurable and are always safe, so they're not
+		// listed here.
+		tlsConfig = &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+			CipherSuites: []uint16{
+				tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+				tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+				tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305,
+				tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+				tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			},
+		}
+		redirectHTTP = http.HandlerFunc(redirectToHTTPS)
+	}
+
+	httpsServer := http.Server{
+		Addr:      fmt.Sprintf(":%d", *httpsPort),
+		Handler:   http.HandlerFunc(pingHandler),
+		TLSConfig: tlsConfig,
+	}
+
+	// the plain-http http.Server (-http-p, 8080 by default; pass
+	// -http-p=80 for the standard port) exists only to send plain-http clients
+	// over to https. with TLSConfig set above, ListenAndServeTLS also
+	// negotiates HTTP/2 over ALPN automatically - nothing else to opt
+	// in here.
+	httpServer := http.Server{
+		Addr:    fmt.Sprintf(":%d", *httpPort),
+		Handler: redirectHTTP,
+	}
+
+	fmt.Printf("listening on port %s (https) and %s (http redirect)\n", httpsServer.Addr, httpServer.Addr)
+
+	go func() {
+		// cert/key args are ignored when TLSConfig.Certificates or
+		// GetCertificate is already populated, which is the case for
+		// both the self-signed and autocert paths above.
+		err := httpsServer.ListenAndServeTLS("", "")
+		if err != nil && err != http.ErrServerClosed {
+			panic(err)
+		}
+	}()
+
+	go func() {
+		err := httpServer.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
+			panic(err)
+		}
+	}()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	<-ctx.Done()
+
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := httpsServer.Shutdown(ctx); err != nil {
+		panic(err)
+	}
+	if err := httpServer.Shutdown(ctx); err != nil {
+		panic(err)
+	}
+
+	fmt.Println("goodbye :)")
+}
+
+func pingHandler(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte("pong!"))
+}
+
+// redirectToHTTPS sends every plain-http request to the same path on the
+// https port with a permanent redirect.
+func redirectToHTTPS(w http.ResponseWriter, r *http.Request) {
+	host, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		host = r.Host
+	}
+
+	target := fmt.Sprintf("https://%s:%d%s", host, *httpsPort, r.URL.RequestURI())
+	http.Redirect(w, r, target, http.StatusMovedPermanently)
+}
+
+func recoverer() {
+	if r := recover(); r != nil {
+		fmt.Fprintf(os.Stderr, "unexpected panic occurred: %v", r)
+		os.Exit(1)
+	}
+}
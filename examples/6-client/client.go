@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+type TodoItem struct {
+	Content string
+	Done    bool
+}
+
+// Client is a small wrapper around http.Client for talking to the
+// todo-item server from [2-routing]. Every prior chapter has been about
+// the server side of net/http; this one is the mirror image.
+type Client struct {
+	baseURL string
+	http    *http.Client
+}
+
+// NewClient builds a Client against baseURL (e.g. "http://localhost:8080").
+//
+// The http.Client is configured rather than left zero-valued:
+//   - Transport gets explicit dial/TLS handshake timeouts and a cap on
+//     idle connections per host, so a slow or hostile server can't hang
+//     the client forever or exhaust its connection pool.
+//   - CheckRedirect caps the redirect chain at 10 hops - http.Client's
+//     own default - but logs each hop, so students can see it happen
+//     instead of it being invisible.
+//   - Jar is a cookiejar.Jar, so a session cookie set by one request is
+//     replayed on the next - see `go run . cookies` in cookiedemo.go for
+//     an actual round trip against a throwaway server, not just this
+//     client being configured and never exercised. It's built with
+//     PublicSuffixList so that, against real multi-label domains, a
+//     cookie set by "example.com" isn't replayed to "evil.com" even
+//     though both end in ".com" - without it, the jar falls back to a
+//     naive "strip the leading label" rule that gets this wrong for
+//     multi-label public suffixes (e.g. "co.uk"). cookiedemo.go's
+//     loopback server can't demonstrate that part; it's here for when
+//     this client talks to a real one.
+func NewClient(baseURL string) (*Client, error) {
+	jar, err := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+	if err != nil {
+		return nil, fmt.Errorf("create cookie jar: %w", err)
+	}
+
+	transport := &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 5 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout: 5 * time.Second,
+		TLSClientConfig:     &tls.Config{MinVersion: tls.VersionTLS12},
+		MaxIdleConnsPerHost: 4,
+	}
+
+	return &Client{
+		baseURL: baseURL,
+		http: &http.Client{
+			Transport: transport,
+			Jar:       jar,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				fmt.Printf("redirected to %s (hop %d)\n", req.URL, len(via))
+				if len(via) >= 10 {
+					return fmt.Errorf("stopped after 10 redirects")
+				}
+				return nil
+			},
+		},
+	}, nil
+}
+
+// List streams and decodes the todo item array without buffering the
+// whole response body first - json.Decoder reads directly from the
+// response body as bytes arrive off the wire.
+func (c *Client) List(ctx context.Context) ([]TodoItem, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/todo-item", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("list todo items: unexpected status %s", resp.Status)
+	}
+
+	var items []TodoItem
+	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return items, nil
+}
+
+// Create sends content/done as a form-encoded body, matching
+// r.ParseForm() on the server - not JSON, which the [2-routing] handlers
+// never learned to read.
+func (c *Client) Create(ctx context.Context, content string, done bool) (int, error) {
+	form := url.Values{
+		"content": {content},
+		"done":    {strconv.FormatBool(done)},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/todo-item", strings.NewReader(form.Encode()))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return 0, fmt.Errorf("create todo item: unexpected status %s", resp.Status)
+	}
+
+	location := resp.Header.Get("Location")
+	var id int
+	if _, err := fmt.Sscanf(location, "/todo-item/%d", &id); err != nil {
+		return 0, fmt.Errorf("parse Location header %q: %w", location, err)
+	}
+	return id, nil
+}
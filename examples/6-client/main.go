@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+var (
+	addr    = flag.String("addr", "http://localhost:8080", "address of the [2-routing] todo-item server")
+	timeout = flag.Duration("timeout", 3*time.Second, "per-request timeout")
+)
+
+// this CLI is the client-side mirror of [2-routing]'s server: same
+// todo-item resource, now from the other end of the socket - list (GET)
+// and create (POST), plus the shutdown-race and cookies demos.
+//
+//	go run . list
+//	go run . create "buy milk" false
+//	go run . shutdown-race
+//	go run . cookies
+func main() {
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: client [-addr=...] [-timeout=...] list|create|shutdown-race|cookies")
+		os.Exit(1)
+	}
+
+	client, err := NewClient(*addr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	switch args[0] {
+	case "list":
+		items, err := client.List(ctx)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		for i, item := range items {
+			fmt.Printf("%d: %s (done=%t)\n", i, item.Content, item.Done)
+		}
+
+	case "create":
+		if len(args) != 3 {
+			fmt.Fprintln(os.Stderr, "usage: client create <content> <done>")
+			os.Exit(1)
+		}
+		done, err := strconv.ParseBool(args[2])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "`done` must be of type bool")
+			os.Exit(1)
+		}
+		id, err := client.Create(ctx, args[1], done)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Printf("created todo item %d\n", id)
+
+	case "shutdown-race":
+		// this sends one List request bounded by the client's own
+		// -timeout; run it while interrupting the [2-routing] server,
+		// whose server.Shutdown(ctx) has a 5-second deadline. a request
+		// already in flight when Shutdown is called is drained, not
+		// dropped, so it still gets a response; one that has no response
+		// by the time -timeout expires fails with
+		// context.DeadlineExceeded instead.
+		if _, err := client.List(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "request lost the race: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("request won the race against shutdown")
+
+	case "cookies":
+		if err := demoCookies(ctx, client); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", args[0])
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bdreece/readyset/examples/7-content-negotiation/render"
+)
+
+type TodoItem = render.TodoItem
+
+var port = flag.Int("p", 8080, "port")
+
+var (
+	mu        sync.RWMutex
+	todoItems = make([]TodoItem, 0)
+)
+
+func main() {
+	flag.Parse()
+
+	router := http.NewServeMux()
+	router.HandleFunc("GET /todo-item", getTodoItems)
+	router.HandleFunc("GET /todo-item/{id}", getTodoItem)
+	router.HandleFunc("HEAD /todo-item/{id}", getTodoItemExists)
+	router.HandleFunc("POST /todo-item", createTodoItem)
+	router.HandleFunc("PUT /todo-item/{id}", replaceTodoItem)
+	router.HandleFunc("PATCH /todo-item/{id}", updateTodoItem)
+	router.HandleFunc("DELETE /todo-item/{id}", deleteTodoItem)
+
+	server := http.Server{
+		Addr:    fmt.Sprintf(":%d", *port),
+		Handler: router,
+	}
+
+	fmt.Printf("listening on port %s\n", server.Addr)
+
+	go func() {
+		err := server.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
+			panic(err)
+		}
+	}()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	<-ctx.Done()
+
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	if err := server.Shutdown(ctx); err != nil {
+		panic(err)
+	}
+
+	fmt.Println("goodbye :)")
+}
+
+// every error response below now goes through the render package instead
+// of an ad-hoc string written by hand; responses with no body (201, 200
+// on writes, HEAD's 200) still call w.WriteHeader directly. compare
+// `render.BadRequest(w, r, "id must be int")` to [2-routing]'s
+// `w.WriteHeader(http.StatusBadRequest); w.Write([]byte("id must be of type int"))` -
+// same outcome, but now every error in the API has the same
+// problem+json shape, and getTodoItem honors Accept instead of always
+// returning JSON.
+
+func getTodoItems(w http.ResponseWriter, r *http.Request) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	render.JSON(w, http.StatusOK, todoItems)
+}
+
+func getTodoItem(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		render.BadRequest(w, r, "`id` must be of type int")
+		return
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if id < 0 || id > len(todoItems)-1 {
+		render.NotFound(w, r, "`id` not found")
+		return
+	}
+
+	render.Negotiate(w, r, http.StatusOK, todoItems[id])
+}
+
+func getTodoItemExists(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		render.BadRequest(w, r, "`id` must be of type int")
+		return
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if id < 0 || id > len(todoItems)-1 {
+		render.NotFound(w, r, "`id` not found")
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func createTodoItem(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		render.BadRequest(w, r, "invalid form")
+		return
+	}
+
+	content := r.FormValue("content")
+	done, err := strconv.ParseBool(r.FormValue("done"))
+	if err != nil {
+		render.BadRequest(w, r, "`done` must be of type bool")
+		return
+	}
+
+	mu.Lock()
+	todoItems = append(todoItems, TodoItem{Content: content, Done: done})
+	id := len(todoItems) - 1
+	mu.Unlock()
+
+	location := fmt.Sprintf("%s/todo-item/%d", r.URL.Host, id)
+	w.Header().Add("Location", location)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func replaceTodoItem(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		render.BadRequest(w, r, "`id` must be of type int")
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		render.BadRequest(w, r, "invalid form")
+		return
+	}
+
+	content := r.FormValue("content")
+	done, err := strconv.ParseBool(r.FormValue("done"))
+	if err != nil {
+		render.BadRequest(w, r, "`done` must be of type bool")
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if id < 0 || id > len(todoItems)-1 {
+		render.NotFound(w, r, "`id` not found")
+		return
+	}
+
+	todoItems[id] = TodoItem{Content: content, Done: done}
+
+	location := fmt.Sprintf("%s/todo-item/%d", r.URL.Host, id)
+	w.Header().Add("Location", location)
+	w.WriteHeader(http.StatusOK)
+}
+
+func updateTodoItem(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		render.BadRequest(w, r, "`id` must be of type int")
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		render.BadRequest(w, r, "invalid form")
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if id < 0 || id > len(todoItems)-1 {
+		render.NotFound(w, r, "`id` not found")
+		return
+	}
+
+	if content := r.FormValue("content"); content != "" {
+		todoItems[id].Content = content
+	}
+
+	if doneStr := r.FormValue("done"); doneStr != "" {
+		done, err := strconv.ParseBool(doneStr)
+		if err != nil {
+			render.BadRequest(w, r, "`done` must be of type bool")
+			return
+		}
+		todoItems[id].Done = done
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func deleteTodoItem(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		render.BadRequest(w, r, "`id` must be of type int")
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if id < 0 || id > len(todoItems)-1 {
+		render.NotFound(w, r, "`id` not found")
+		return
+	}
+
+	todoItems = append(todoItems[:id], todoItems[id+1:]...)
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,133 @@
+// Package render centralizes how handlers write responses, so that every
+// handler in [7-content-negotiation] sends the same Content-Type headers
+// and the same error shape instead of each inventing its own ad-hoc
+// "text/plain" strings like the ones in [2-routing] ("`id` must be of
+// type int").
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Problem is an RFC 7807 "problem detail" document, returned for every
+// error response regardless of which handler produced it.
+type Problem struct {
+	Type     string `json:"type,omitempty"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+}
+
+func (p *Problem) Error() string {
+	return fmt.Sprintf("%d %s: %s", p.Status, p.Title, p.Detail)
+}
+
+// writeProblem writes status and detail as an "application/problem+json"
+// document, per RFC 7807. Instance is filled in from the request's path
+// so clients (and logs) can tell which request the problem came from.
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	problem := &Problem{
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(problem)
+}
+
+// BadRequest writes a 400 problem+json document with detail as the
+// human-readable explanation, e.g. render.BadRequest(w, r, "id must be int").
+func BadRequest(w http.ResponseWriter, r *http.Request, detail string) {
+	writeProblem(w, r, http.StatusBadRequest, detail)
+}
+
+// NotFound writes a 404 problem+json document.
+func NotFound(w http.ResponseWriter, r *http.Request, detail string) {
+	writeProblem(w, r, http.StatusNotFound, detail)
+}
+
+// InternalServerError writes a 500 problem+json document. detail is
+// intentionally generic - it's sent to the client, so it shouldn't leak
+// the underlying error.
+func InternalServerError(w http.ResponseWriter, r *http.Request) {
+	writeProblem(w, r, http.StatusInternalServerError, "an unexpected error occurred")
+}
+
+// JSON sets Content-Type: application/json; charset=utf-8 and encodes v
+// as the response body, unlike [2-routing]'s handlers, which called
+// json.NewEncoder(w).Encode(...) without ever setting a Content-Type.
+func JSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// TodoItem mirrors the shape handlers pass to Negotiate - kept here
+// rather than imported from main.go so this package has no dependency
+// on the rest of the chapter.
+type TodoItem struct {
+	Content string
+	Done    bool
+}
+
+// Negotiate inspects the request's Accept header and writes item as
+// either JSON (the default, and what's sent for "*/*" or "application/json"),
+// or as application/x-www-form-urlencoded / text/plain for clients that
+// ask for it explicitly - mirroring the form bodies those same clients
+// send us in [6-client].
+func Negotiate(w http.ResponseWriter, r *http.Request, status int, item TodoItem) {
+	for _, accept := range parseAccept(r.Header.Get("Accept")) {
+		switch accept {
+		case "application/x-www-form-urlencoded":
+			form := url.Values{
+				"content": {item.Content},
+				"done":    {fmt.Sprintf("%t", item.Done)},
+			}
+			w.Header().Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(form.Encode()))
+			return
+
+		case "text/plain":
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.WriteHeader(status)
+			fmt.Fprintf(w, "%s\t%t\n", item.Content, item.Done)
+			return
+
+		case "application/json", "*/*":
+			JSON(w, status, item)
+			return
+		}
+	}
+
+	// no entry in Accept that we understand: RFC 9110 says to either
+	// send a 406, or fall back to a default representation. we do the
+	// latter, same as most JSON APIs in the wild.
+	JSON(w, status, item)
+}
+
+// parseAccept splits an Accept header into its media types, in the order
+// they appear in the header. Parameters, including "q", are dropped and
+// play no part in the order (a real implementation would sort by "q";
+// this chapter keeps it to the common case of one or two types with no
+// q value, which is what [6-client] and curl send).
+func parseAccept(header string) []string {
+	if header == "" {
+		return []string{"*/*"}
+	}
+
+	var types []string
+	for _, part := range strings.Split(header, ",") {
+		mediaType, _, _ := strings.Cut(strings.TrimSpace(part), ";")
+		types = append(types, mediaType)
+	}
+	return types
+}
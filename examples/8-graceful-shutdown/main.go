@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+)
+
+var (
+	port = flag.Int("p", 8080, "port")
+	// shutdownTimeout has to outlast /slow's sleep for the graceful path
+	// to actually win - see shutdown() and /very-slow below for what
+	// happens when it doesn't.
+	shutdownTimeout = flag.Duration("shutdown-timeout", 15*time.Second, "deadline for the graceful server.Shutdown before falling back to server.Close")
+)
+
+// inFlight tracks requests server.Shutdown can't see on its own.
+// Shutdown waits for listeners to close and tracked connections to go
+// idle, but it has no idea a handler that hijacked its connection
+// (long-poll, WebSocket) is still running - that's what this WaitGroup is
+// for. It counts handlers until ServeHTTP returns; a goroutine a handler
+// spawns and leaves running is not counted.
+var inFlight sync.WaitGroup
+
+// trackInFlight is a small middleware: Add before the handler runs,
+// Done after, regardless of how the handler returns.
+func trackInFlight(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inFlight.Add(1)
+		defer inFlight.Done()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// backgroundWorker simulates a long-lived resource - a DB connection
+// pool, a message queue consumer - that main owns independently of any
+// one request and needs an explicit signal to stop.
+type backgroundWorker struct {
+	stop chan struct{}
+	done chan struct{}
+}
+
+func startBackgroundWorker() *backgroundWorker {
+	w := &backgroundWorker{
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	go func() {
+		defer close(w.done)
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				fmt.Println("background worker: tick")
+			case <-w.stop:
+				fmt.Println("background worker: stopping")
+				return
+			}
+		}
+	}()
+
+	return w
+}
+
+func (w *backgroundWorker) Shutdown() {
+	close(w.stop)
+	<-w.done
+}
+
+func main() {
+	defer recoverer()
+	flag.Parse()
+
+	worker := startBackgroundWorker()
+
+	router := http.NewServeMux()
+	router.HandleFunc("GET /ping", pingHandler)
+	// try `curl localhost:8080/slow` and then Ctrl+C the server within
+	// the 10 seconds: with the default -shutdown-timeout=15s, the log
+	// line below only prints once the handler actually returns, proving
+	// Shutdown waited for it instead of dropping the connection.
+	router.HandleFunc("GET /slow", slowHandler)
+	// /very-slow is the opposite demo: its sleep outlasts even the
+	// default -shutdown-timeout, so Shutdown's deadline always expires
+	// first and shutdown() falls back to the abortive server.Close,
+	// which resets this connection mid-sleep. the client never receives
+	// "done sleeping"; the handler itself still finishes its sleep, and
+	// shutdown() waits for it via inFlight before main returns.
+	router.HandleFunc("GET /very-slow", verySlowHandler)
+
+	server := http.Server{
+		Addr:    fmt.Sprintf(":%d", *port),
+		Handler: trackInFlight(router),
+	}
+
+	// RegisterOnShutdown runs its functions in their own goroutines as
+	// soon as Shutdown is called, in parallel with it waiting on
+	// in-flight HTTP requests - exactly where a hijacked connection's
+	// own shutdown signal belongs, since http.Server stops tracking a
+	// connection the moment it's hijacked.
+	server.RegisterOnShutdown(worker.Shutdown)
+
+	fmt.Printf("listening on port %s\n", server.Addr)
+
+	go func() {
+		err := server.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
+			panic(err)
+		}
+	}()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	<-ctx.Done()
+
+	shutdown(&server)
+
+	fmt.Println("goodbye :)")
+}
+
+// shutdown tries a graceful server.Shutdown first - which drains
+// in-flight requests (tracked natively for ordinary connections, and via
+// inFlight/RegisterOnShutdown for everything else) - and falls back to
+// the abortive server.Close if that doesn't finish before shutdownTimeout.
+// Close immediately closes every open listener and connection, dropping
+// whatever was still in flight; it's the last resort, not the first
+// choice.
+func shutdown(server *http.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+	defer cancel()
+
+	// Shutdown already blocks until draining finishes or ctx is done,
+	// returning ctx.Err() if the deadline won the race - no need for a second
+	// goroutine/select on ctx.Done() here, and one only invites a race
+	// between that select and Shutdown's own internal one.
+	if err := server.Shutdown(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "graceful shutdown timed out (%v), forcing close\n", err)
+		if err := server.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "close failed: %v\n", err)
+		}
+	}
+
+	// RegisterOnShutdown's callback and Shutdown's own connection
+	// tracking cover everything the server can see; inFlight covers
+	// handlers trackInFlight wrapped that it can't, e.g. one that hijacked
+	// its connection and hasn't returned from ServeHTTP yet, or one still
+	// running after server.Close dropped its connection.
+	inFlight.Wait()
+}
+
+func pingHandler(w http.ResponseWriter, r *http.Request) {
+	_, _ = w.Write([]byte("pong!"))
+}
+
+func slowHandler(w http.ResponseWriter, r *http.Request) {
+	time.Sleep(10 * time.Second)
+	fmt.Println("slow handler: done sleeping")
+	_, _ = w.Write([]byte("done sleeping\n"))
+}
+
+func verySlowHandler(w http.ResponseWriter, r *http.Request) {
+	time.Sleep(20 * time.Second)
+	fmt.Println("very-slow handler: done sleeping")
+	_, _ = w.Write([]byte("done sleeping\n"))
+}
+
+func recoverer() {
+	if r := recover(); r != nil {
+		fmt.Fprintf(os.Stderr, "unexpected panic occurred: %v", r)
+		os.Exit(1)
+	}
+}